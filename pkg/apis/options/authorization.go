@@ -0,0 +1,27 @@
+package options
+
+// AuthorizationPolicyOptions configures an optional Rego policy evaluated
+// per-request after authentication. When set, it supersedes the ad-hoc
+// allowlist flags (EmailDomains, WhitelistDomains, provider group checks,
+// per-upstream AuthorizationRules) for any upstream it covers; those flags
+// remain in effect wherever no policy is configured, so adopting a policy
+// is opt-in and incremental.
+type AuthorizationPolicyOptions struct {
+	// PolicyFile is the path to a Rego policy file evaluated against the
+	// authorization decision input. Mutually exclusive with Policy.
+	PolicyFile string `flag:"authorization-policy-file" cfg:"authorization_policy_file"`
+
+	// Policy is an inline Rego policy, useful for small policies defined
+	// directly in the config file. Mutually exclusive with PolicyFile.
+	Policy string `flag:"authorization-policy" cfg:"authorization_policy"`
+
+	// Query is the Rego query evaluated against the compiled policy, eg.
+	// "data.oauth2_proxy.authz.result".
+	Query string `flag:"authorization-policy-query" cfg:"authorization_policy_query"`
+}
+
+func authorizationPolicyDefaults() AuthorizationPolicyOptions {
+	return AuthorizationPolicyOptions{
+		Query: "data.oauth2_proxy.authz.result",
+	}
+}
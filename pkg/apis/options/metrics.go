@@ -0,0 +1,73 @@
+package options
+
+import "fmt"
+
+// MetricsOptions configures the Prometheus metrics endpoint.
+type MetricsOptions struct {
+	// BindAddress is the <addr>:<port> the metrics endpoint listens on. If
+	// empty, no metrics endpoint is served.
+	BindAddress string `flag:"metrics-address" cfg:"metrics_address"`
+
+	// Path is the path the metrics endpoint is served on.
+	Path string `flag:"metrics-path" cfg:"metrics_path"`
+
+	TLSCertFile string `flag:"metrics-tls-cert-file" cfg:"metrics_tls_cert_file"`
+	TLSKeyFile  string `flag:"metrics-tls-key-file" cfg:"metrics_tls_key_file"`
+}
+
+func metricsDefaults() MetricsOptions {
+	return MetricsOptions{
+		Path: "/metrics",
+	}
+}
+
+// Validate checks that the metrics TLS configuration, if any, is complete.
+func (o *MetricsOptions) Validate() error {
+	if (o.TLSCertFile == "") != (o.TLSKeyFile == "") {
+		return fmt.Errorf("metrics-tls-cert-file and metrics-tls-key-file must both be set to serve metrics over TLS")
+	}
+	return nil
+}
+
+// OTelOptions configures OpenTelemetry tracing of OAuth flows and upstream
+// requests.
+type OTelOptions struct {
+	// OTLPEndpoint is the OTLP collector endpoint spans are exported to. If
+	// empty, tracing is disabled.
+	OTLPEndpoint string `flag:"otel-exporter-otlp-endpoint" cfg:"otel_exporter_otlp_endpoint"`
+
+	// SamplingRatio is the fraction of traces that are sampled, from 0
+	// (none) to 1 (all).
+	SamplingRatio float64 `flag:"otel-sampling-ratio" cfg:"otel_sampling_ratio"`
+
+	// ServiceName identifies this instance in exported spans.
+	ServiceName string `flag:"otel-service-name" cfg:"otel_service_name"`
+
+	// Insecure disables TLS on the connection to the OTLP collector. Operators
+	// shipping traces to a remote collector should leave this false and use
+	// CAPath/InsecureSkipTLSVerify to configure the TLS connection instead.
+	Insecure bool `flag:"otel-exporter-otlp-insecure" cfg:"otel_exporter_otlp_insecure"`
+
+	// CAPath is the path to a CA certificate file used to verify the OTLP
+	// collector's certificate, for collectors using a private CA.
+	CAPath string `flag:"otel-exporter-otlp-ca-path" cfg:"otel_exporter_otlp_ca_path"`
+
+	// InsecureSkipTLSVerify disables verification of the OTLP collector's
+	// TLS certificate. This option should only be used for testing.
+	InsecureSkipTLSVerify bool `flag:"otel-exporter-otlp-insecure-skip-tls-verify" cfg:"otel_exporter_otlp_insecure_skip_tls_verify"`
+}
+
+func otelDefaults() OTelOptions {
+	return OTelOptions{
+		SamplingRatio: 1.0,
+		ServiceName:   "oauth2-proxy",
+	}
+}
+
+// Validate checks that SamplingRatio is within its valid range.
+func (o *OTelOptions) Validate() error {
+	if o.SamplingRatio < 0 || o.SamplingRatio > 1 {
+		return fmt.Errorf("otel-sampling-ratio must be between 0 and 1, got %v", o.SamplingRatio)
+	}
+	return nil
+}
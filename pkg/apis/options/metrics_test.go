@@ -0,0 +1,42 @@
+package options
+
+import "testing"
+
+func TestMetricsOptionsValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    MetricsOptions
+		wantErr bool
+	}{
+		{"no tls", MetricsOptions{}, false},
+		{"cert and key", MetricsOptions{TLSCertFile: "cert", TLSKeyFile: "key"}, false},
+		{"cert only", MetricsOptions{TLSCertFile: "cert"}, true},
+		{"key only", MetricsOptions{TLSKeyFile: "key"}, true},
+	}
+
+	for _, c := range cases {
+		if err := c.opts.Validate(); (err != nil) != c.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", c.name, err, c.wantErr)
+		}
+	}
+}
+
+func TestOTelOptionsValidate(t *testing.T) {
+	cases := []struct {
+		ratio   float64
+		wantErr bool
+	}{
+		{0, false},
+		{0.5, false},
+		{1, false},
+		{-0.1, true},
+		{1.1, true},
+	}
+
+	for _, c := range cases {
+		opts := OTelOptions{SamplingRatio: c.ratio}
+		if err := opts.Validate(); (err != nil) != c.wantErr {
+			t.Errorf("SamplingRatio=%v: Validate() error = %v, wantErr %v", c.ratio, err, c.wantErr)
+		}
+	}
+}
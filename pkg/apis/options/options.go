@@ -43,9 +43,12 @@ type Options struct {
 	Banner              string `flag:"banner" cfg:"banner"`
 	Footer              string `flag:"footer" cfg:"footer"`
 
-	Cookie  Cookie         `cfg:",squash"`
-	Session SessionOptions `cfg:",squash"`
-	Logging Logging        `cfg:",squash"`
+	Cookie        Cookie                     `cfg:",squash"`
+	Session       SessionOptions             `cfg:",squash"`
+	Logging       Logging                    `cfg:",squash"`
+	Authorization AuthorizationPolicyOptions `cfg:",squash"`
+	Metrics       MetricsOptions             `cfg:",squash"`
+	OTel          OTelOptions                `cfg:",squash"`
 
 	// Not used in the legacy config, name not allowed to match an external key (upstreams)
 	// TODO(JoelSpeed): Rename when legacy config is removed
@@ -53,6 +56,8 @@ type Options struct {
 
 	Providers Providers `cfg:",internal"`
 
+	// SkipAuthRegex applies to all upstreams that don't define their own
+	// AuthorizationRules (see UpstreamServers).
 	SkipAuthRegex         []string `flag:"skip-auth-regex" cfg:"skip_auth_regex"`
 	SkipAuthStripHeaders  bool     `flag:"skip-auth-strip-headers" cfg:"skip_auth_strip_headers"`
 	SkipJwtBearerTokens   bool     `flag:"skip-jwt-bearer-tokens" cfg:"skip_jwt_bearer_tokens"`
@@ -114,6 +119,9 @@ func NewOptions() *Options {
 		DisplayHtpasswdForm: true,
 		Cookie:              cookieDefaults(),
 		Session:             sessionOptionsDefaults(),
+		Authorization:       authorizationPolicyDefaults(),
+		Metrics:             metricsDefaults(),
+		OTel:                otelDefaults(),
 		UpstreamServers:     Upstreams{},
 		// AzureTenant:                      "common",
 		SetXAuthRequest:   false,
@@ -137,6 +145,22 @@ func NewOptions() *Options {
 	}
 }
 
+// Validate checks that Options is internally consistent, and compiles the
+// per-upstream authorization rules so SkipsAuth/IsAuthorized are ready to be
+// evaluated in the request handler chain.
+func (o *Options) Validate() error {
+	if err := o.Metrics.Validate(); err != nil {
+		return err
+	}
+	if err := o.OTel.Validate(); err != nil {
+		return err
+	}
+	if err := o.UpstreamServers.Compile(); err != nil {
+		return err
+	}
+	return nil
+}
+
 // NewFlagSet creates a new FlagSet with all of the flags required by Options
 func NewFlagSet() *pflag.FlagSet {
 	flagSet := pflag.NewFlagSet("oauth2-proxy", pflag.ExitOnError)
@@ -178,7 +202,7 @@ func NewFlagSet() *pflag.FlagSet {
 	flagSet.String("proxy-prefix", "/oauth2", "the url root path that this proxy should be nested under (e.g. /<oauth2>/sign_in)")
 	flagSet.String("ping-path", "/ping", "the ping endpoint that can be used for basic health checks")
 	flagSet.String("ping-user-agent", "", "special User-Agent that will be used for basic health checks")
-	flagSet.String("session-store-type", "cookie", "the session storage provider to use")
+	flagSet.String("session-store-type", "cookie", "the session storage provider to use (one of cookie, redis, dynamodb, memcached)")
 	flagSet.Bool("session-cookie-minimal", false, "strip OAuth tokens from cookie session stores if they aren't needed (cookie session store only)")
 	flagSet.String("redis-connection-url", "", "URL of redis server for redis session storage (eg: redis://HOST[:PORT])")
 	flagSet.String("redis-password", "", "Redis password. Applicable for all Redis configurations. Will override any password set in `--redis-connection-url`")
@@ -191,6 +215,30 @@ func NewFlagSet() *pflag.FlagSet {
 	flagSet.Bool("redis-use-cluster", false, "Connect to redis cluster. Must set --redis-cluster-connection-urls to use this feature")
 	flagSet.StringSlice("redis-cluster-connection-urls", []string{}, "List of Redis cluster connection URLs (eg redis://HOST[:PORT]). Used in conjunction with --redis-use-cluster")
 
+	flagSet.String("dynamodb-table", "", "DynamoDB table to use for dynamodb session storage (must already exist, hash key 'id')")
+	flagSet.String("dynamodb-region", "", "AWS region of the DynamoDB table used for dynamodb session storage")
+	flagSet.String("dynamodb-endpoint", "", "Custom DynamoDB endpoint to use instead of the default AWS endpoint (eg for local testing)")
+	flagSet.Int("dynamodb-session-ttl-seconds", 168*3600, "Time to live for DynamoDB sessions, using the table's TTL attribute, in seconds")
+
+	flagSet.StringSlice("memcached-server", []string{}, "Memcached server(s) to use for memcached session storage (eg HOST:PORT, may be given multiple times)")
+	flagSet.Int("memcached-session-ttl-seconds", 168*3600, "Time to live for Memcached sessions in seconds")
+
+	flagSet.String("authorization-policy-file", "", "path to a Rego policy file evaluated per-request after authentication (see --authorization-policy for an inline alternative)")
+	flagSet.String("authorization-policy", "", "inline Rego policy evaluated per-request after authentication, as an alternative to --authorization-policy-file")
+	flagSet.String("authorization-policy-query", "data.oauth2_proxy.authz.result", "the Rego query to evaluate against the compiled authorization policy")
+
+	flagSet.String("metrics-address", "", "[<addr>]:<port> to listen on for Prometheus metrics. If not set, metrics are not served")
+	flagSet.String("metrics-path", "/metrics", "the path the metrics endpoint is served on")
+	flagSet.String("metrics-tls-cert-file", "", "path to certificate file for the metrics endpoint")
+	flagSet.String("metrics-tls-key-file", "", "path to private key file for the metrics endpoint")
+
+	flagSet.String("otel-exporter-otlp-endpoint", "", "OTLP collector endpoint to export traces to. If not set, tracing is disabled")
+	flagSet.Float64("otel-sampling-ratio", 1.0, "fraction of traces to sample, from 0 (none) to 1 (all)")
+	flagSet.String("otel-service-name", "oauth2-proxy", "service name to report in exported traces")
+	flagSet.Bool("otel-exporter-otlp-insecure", false, "disable TLS on the connection to the OTLP collector")
+	flagSet.String("otel-exporter-otlp-ca-path", "", "path to a CA certificate file used to verify the OTLP collector's certificate")
+	flagSet.Bool("otel-exporter-otlp-insecure-skip-tls-verify", false, "skip verification of the OTLP collector's TLS certificate (testing only)")
+
 	flagSet.String("signature-key", "", "GAP-Signature request signature key (algorithm:secretkey)")
 	flagSet.Bool("gcp-healthchecks", false, "Enable GCP/GKE healthcheck endpoints")
 
@@ -0,0 +1,41 @@
+package options
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestOptionsValidateCompilesUpstreams(t *testing.T) {
+	opts := &Options{
+		UpstreamServers: Upstreams{
+			{
+				ID:   "app",
+				Path: "/",
+				AuthorizationRules: AuthorizationRules{
+					SkipAuthRegex: []string{"^/ping$"},
+				},
+			},
+		},
+	}
+
+	if err := opts.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	upstream := &opts.UpstreamServers[0]
+	req, err := http.NewRequest(http.MethodGet, "/ping", nil)
+	if err != nil {
+		t.Fatalf("unexpected error building request: %v", err)
+	}
+	if !upstream.SkipsAuth(req) {
+		t.Error("expected Validate to have compiled the upstream's authorization rules")
+	}
+}
+
+func TestOptionsValidatePropagatesSubOptionErrors(t *testing.T) {
+	opts := &Options{OTel: OTelOptions{SamplingRatio: 2}}
+
+	if err := opts.Validate(); err == nil {
+		t.Fatal("expected an error from an invalid OTel sampling ratio")
+	}
+}
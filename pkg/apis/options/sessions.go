@@ -0,0 +1,75 @@
+package options
+
+// SessionOptions contains configuration for the SessionStore used to store
+// the user's authentication session between requests.
+//
+// The Type field selects which backend is used. Backends are looked up by
+// name from the registry in the sessions package (see sessions.StoreFactory),
+// so additional backends can be added without modifying this struct.
+type SessionOptions struct {
+	Type string `flag:"session-store-type" cfg:"session_store_type"`
+
+	Cookie    CookieStoreOptions    `cfg:",squash"`
+	Redis     RedisStoreOptions     `cfg:",squash"`
+	DynamoDB  DynamoDBStoreOptions  `cfg:",squash"`
+	Memcached MemcachedStoreOptions `cfg:",squash"`
+}
+
+// Session store type names, used as the value of --session-store-type and
+// as the registration key in the sessions.StoreFactory registry.
+const (
+	CookieSessionStoreType    = "cookie"
+	RedisSessionStoreType     = "redis"
+	DynamoDBSessionStoreType  = "dynamodb"
+	MemcachedSessionStoreType = "memcached"
+)
+
+// CookieStoreOptions contains configuration for the cookie-based SessionStore.
+type CookieStoreOptions struct {
+	Minimal bool `flag:"session-cookie-minimal" cfg:"session_cookie_minimal"`
+}
+
+// RedisStoreOptions contains configuration for the Redis-based SessionStore.
+type RedisStoreOptions struct {
+	ConnectionURL          string   `flag:"redis-connection-url" cfg:"redis_connection_url"`
+	Password               string   `flag:"redis-password" cfg:"redis_password"`
+	UseSentinel            bool     `flag:"redis-use-sentinel" cfg:"redis_use_sentinel"`
+	SentinelPassword       string   `flag:"redis-sentinel-password" cfg:"redis_sentinel_password"`
+	SentinelMasterName     string   `flag:"redis-sentinel-master-name" cfg:"redis_sentinel_master_name"`
+	SentinelConnectionURLs []string `flag:"redis-sentinel-connection-urls" cfg:"redis_sentinel_connection_urls"`
+	UseCluster             bool     `flag:"redis-use-cluster" cfg:"redis_use_cluster"`
+	ClusterConnectionURLs  []string `flag:"redis-cluster-connection-urls" cfg:"redis_cluster_connection_urls"`
+	CAPath                 string   `flag:"redis-ca-path" cfg:"redis_ca_path"`
+	InsecureSkipTLSVerify  bool     `flag:"redis-insecure-skip-tls-verify" cfg:"redis_insecure_skip_tls_verify"`
+}
+
+// DynamoDBStoreOptions contains configuration for the DynamoDB-based SessionStore.
+type DynamoDBStoreOptions struct {
+	Table    string `flag:"dynamodb-table" cfg:"dynamodb_table"`
+	Region   string `flag:"dynamodb-region" cfg:"dynamodb_region"`
+	Endpoint string `flag:"dynamodb-endpoint" cfg:"dynamodb_endpoint"`
+	TTL      int    `flag:"dynamodb-session-ttl-seconds" cfg:"dynamodb_session_ttl_seconds"`
+}
+
+// MemcachedStoreOptions contains configuration for the Memcached-based SessionStore.
+type MemcachedStoreOptions struct {
+	Servers []string `flag:"memcached-server" cfg:"memcached_servers"`
+	TTL     int      `flag:"memcached-session-ttl-seconds" cfg:"memcached_session_ttl_seconds"`
+}
+
+// sessionOptionsDefaults returns the default SessionOptions, used by cookie
+// session storage unless a different --session-store-type is chosen.
+func sessionOptionsDefaults() SessionOptions {
+	return SessionOptions{
+		Type: CookieSessionStoreType,
+		Cookie: CookieStoreOptions{
+			Minimal: false,
+		},
+		DynamoDB: DynamoDBStoreOptions{
+			TTL: 168 * 3600,
+		},
+		Memcached: MemcachedStoreOptions{
+			TTL: 168 * 3600,
+		},
+	}
+}
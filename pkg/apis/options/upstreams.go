@@ -0,0 +1,241 @@
+package options
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Upstreams is a collection of definitions for upstream servers.
+type Upstreams []Upstream
+
+// Upstream represents the configuration for an upstream server that
+// oauth2-proxy should proxy requests to once a request has been
+// authenticated and authorized.
+type Upstream struct {
+	// ID should be a unique identifier for the upstream. This value is
+	// required for all upstream types.
+	ID string `json:"id,omitempty"`
+
+	// Path is used to map requests to the upstream server. The closest match
+	// will take precedence and all Paths must be unique.
+	Path string `json:"path,omitempty"`
+
+	// URI is the address of the upstream server. This should be used when
+	// the upstream server is a web server.
+	URI string `json:"uri,omitempty"`
+
+	// InsecureSkipTLSVerify disables TLS verification for requests to this
+	// upstream. This option is insecure and will allow potential Man-In-The-Middle attacks.
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+
+	// PassHostHeader determines whether the request host header should be
+	// proxied to the upstream server. Defaults to true.
+	PassHostHeader *bool `json:"passHostHeader,omitempty"`
+
+	// ProxyWebSockets enables proxying of websockets to upstream servers.
+	// Defaults to true.
+	ProxyWebSockets *bool `json:"proxyWebSockets,omitempty"`
+
+	// FlushInterval is the period between flushing the response buffer when
+	// streaming response from the upstream.
+	FlushInterval *time.Duration `json:"flushInterval,omitempty"`
+
+	// AuthorizationRules contains the access control rules that apply to
+	// requests routed to this upstream. If omitted, the global
+	// Options.SkipAuthRegex, EmailDomains, WhitelistDomains and provider
+	// group checks continue to apply, preserving today's behaviour.
+	AuthorizationRules
+
+	// compiled holds the regex/method matchers compiled from
+	// AuthorizationRules by Upstreams.Compile.
+	compiled *compiledAuthorizationRules
+}
+
+// AuthorizationRules describes the per-upstream authorization policy: who
+// is allowed through, and which requests can skip authentication entirely.
+type AuthorizationRules struct {
+	// SkipAuthRegex bypasses authentication for requests whose path matches
+	// one of these regular expressions, scoped to this upstream only.
+	SkipAuthRegex []string `json:"skipAuthRegex,omitempty"`
+
+	// SkipAuthRoutes bypasses authentication for requests matching Path,
+	// restricted to the given HTTP Methods. Use this when only some methods
+	// on a path should be public (eg. GET /healthz but not POST /healthz).
+	SkipAuthRoutes []SkipAuthRoute `json:"skipAuthRoutes,omitempty"`
+
+	// AllowedGroups restricts access to this upstream to sessions whose
+	// provider groups intersect this list. Empty means no group
+	// restriction is applied at the upstream level.
+	AllowedGroups []string `json:"allowedGroups,omitempty"`
+
+	// AllowedEmails restricts access to this upstream to these exact email
+	// addresses, in addition to any globally authenticated identity.
+	AllowedEmails []string `json:"allowedEmails,omitempty"`
+
+	// AllowedEmailDomains restricts access to this upstream to emails in
+	// these domains. Use "*" to allow any authenticated email.
+	AllowedEmailDomains []string `json:"allowedEmailDomains,omitempty"`
+}
+
+// SkipAuthRoute pairs a path regex with the set of HTTP methods it applies
+// to. An empty Methods list matches all methods.
+type SkipAuthRoute struct {
+	Path    string   `json:"path,omitempty"`
+	Methods []string `json:"methods,omitempty"`
+}
+
+type compiledAuthorizationRules struct {
+	skipAuthRegex  []*regexp.Regexp
+	skipAuthRoutes []compiledSkipAuthRoute
+	allowedGroups  map[string]struct{}
+}
+
+type compiledSkipAuthRoute struct {
+	regex   *regexp.Regexp
+	methods map[string]struct{}
+}
+
+// Compile compiles the authorization rules of every upstream, so that
+// SkipsAuth and IsAuthorized can be evaluated per-request without
+// recompiling regular expressions on every call.
+func (u Upstreams) Compile() error {
+	for i := range u {
+		if err := u[i].compile(); err != nil {
+			return fmt.Errorf("error compiling authorization rules for upstream %q: %w", u[i].ID, err)
+		}
+	}
+	return nil
+}
+
+func (u *Upstream) compile() error {
+	c := &compiledAuthorizationRules{}
+
+	for _, pattern := range u.SkipAuthRegex {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("error compiling skip-auth-regex %q: %w", pattern, err)
+		}
+		c.skipAuthRegex = append(c.skipAuthRegex, regex)
+	}
+
+	for _, route := range u.SkipAuthRoutes {
+		regex, err := regexp.Compile(route.Path)
+		if err != nil {
+			return fmt.Errorf("error compiling skip-auth-route path %q: %w", route.Path, err)
+		}
+		methods := make(map[string]struct{}, len(route.Methods))
+		for _, method := range route.Methods {
+			methods[method] = struct{}{}
+		}
+		c.skipAuthRoutes = append(c.skipAuthRoutes, compiledSkipAuthRoute{regex: regex, methods: methods})
+	}
+
+	if len(u.AllowedGroups) > 0 {
+		c.allowedGroups = make(map[string]struct{}, len(u.AllowedGroups))
+		for _, g := range u.AllowedGroups {
+			c.allowedGroups[g] = struct{}{}
+		}
+	}
+
+	u.compiled = c
+	return nil
+}
+
+// SkipsAuth reports whether req should bypass authentication entirely based
+// on this upstream's SkipAuthRegex and SkipAuthRoutes.
+func (u *Upstream) SkipsAuth(req *http.Request) bool {
+	if u.compiled == nil {
+		return false
+	}
+
+	for _, regex := range u.compiled.skipAuthRegex {
+		if regex.MatchString(req.URL.Path) {
+			return true
+		}
+	}
+
+	for _, route := range u.compiled.skipAuthRoutes {
+		if !route.regex.MatchString(req.URL.Path) {
+			continue
+		}
+		if len(route.methods) == 0 {
+			return true
+		}
+		if _, ok := route.methods[req.Method]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsAuthorized reports whether a session with the given email and groups is
+// permitted to access this upstream. It returns true when no
+// AllowedGroups/AllowedEmails/AllowedEmailDomains are configured, since the
+// caller is expected to have already enforced the global policy in that case.
+func (u *Upstream) IsAuthorized(email string, groups []string) bool {
+	if len(u.AllowedGroups) == 0 && len(u.AllowedEmails) == 0 && len(u.AllowedEmailDomains) == 0 {
+		return true
+	}
+
+	for _, allowed := range u.AllowedEmails {
+		if allowed == email {
+			return true
+		}
+	}
+
+	for _, domain := range u.AllowedEmailDomains {
+		if domain == "*" || hasEmailDomain(email, domain) {
+			return true
+		}
+	}
+
+	if len(u.AllowedGroups) > 0 {
+		allowedGroups := u.allowedGroupSet()
+		for _, g := range groups {
+			if _, ok := allowedGroups[g]; ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// allowedGroupSet returns the AllowedGroups lookup set precomputed by
+// compile, falling back to building it on the fly if Upstreams.Compile
+// hasn't been called yet.
+func (u *Upstream) allowedGroupSet() map[string]struct{} {
+	if u.compiled != nil {
+		return u.compiled.allowedGroups
+	}
+
+	allowedGroups := make(map[string]struct{}, len(u.AllowedGroups))
+	for _, g := range u.AllowedGroups {
+		allowedGroups[g] = struct{}{}
+	}
+	return allowedGroups
+}
+
+func hasEmailDomain(email, domain string) bool {
+	at := len(email) - len(domain) - 1
+	return at > 0 && email[at] == '@' && email[at+1:] == domain
+}
+
+// legacyUpstreamsFlagSet returns the flags used to build a single Upstream
+// from the legacy (pre-multi-upstream) command line flags.
+func legacyUpstreamsFlagSet() *pflag.FlagSet {
+	flagSet := pflag.NewFlagSet("legacy-upstreams", pflag.ExitOnError)
+
+	flagSet.StringSlice("upstream", []string{}, "the http url(s) of the upstream endpoint, file:// paths for static files or static://<status_code> for static response. Routing is based on the path")
+	flagSet.Bool("pass-host-header", true, "pass the request Host Header to upstream")
+	flagSet.Bool("proxy-websockets", true, "enables WebSocket proxying")
+	flagSet.Duration("flush-interval", 0, "period between flushing response buffer when streaming response to client")
+	flagSet.Bool("ssl-upstream-insecure-skip-verify", false, "skip validation of certificates presented when using HTTPS upstreams")
+
+	return flagSet
+}
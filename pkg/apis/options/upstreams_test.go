@@ -0,0 +1,84 @@
+package options
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestUpstreamSkipsAuth(t *testing.T) {
+	upstreams := Upstreams{
+		{
+			ID:   "app",
+			Path: "/",
+			AuthorizationRules: AuthorizationRules{
+				SkipAuthRegex: []string{"^/ping$"},
+				SkipAuthRoutes: []SkipAuthRoute{
+					{Path: "^/healthz$", Methods: []string{"GET"}},
+				},
+			},
+		},
+	}
+
+	if err := upstreams.Compile(); err != nil {
+		t.Fatalf("unexpected error compiling upstreams: %v", err)
+	}
+
+	upstream := &upstreams[0]
+
+	cases := []struct {
+		method string
+		path   string
+		want   bool
+	}{
+		{"GET", "/ping", true},
+		{"GET", "/healthz", true},
+		{"POST", "/healthz", false},
+		{"GET", "/secure", false},
+	}
+
+	for _, c := range cases {
+		req, err := http.NewRequest(c.method, c.path, nil)
+		if err != nil {
+			t.Fatalf("unexpected error building request: %v", err)
+		}
+		if got := upstream.SkipsAuth(req); got != c.want {
+			t.Errorf("SkipsAuth(%s %s) = %v, want %v", c.method, c.path, got, c.want)
+		}
+	}
+}
+
+func TestUpstreamIsAuthorized(t *testing.T) {
+	upstream := &Upstream{
+		ID: "app",
+		AuthorizationRules: AuthorizationRules{
+			AllowedGroups:       []string{"admins"},
+			AllowedEmails:       []string{"alice@example.com"},
+			AllowedEmailDomains: []string{"trusted.com"},
+		},
+	}
+
+	cases := []struct {
+		email  string
+		groups []string
+		want   bool
+	}{
+		{"alice@example.com", nil, true},
+		{"bob@trusted.com", nil, true},
+		{"bob@example.com", []string{"admins"}, true},
+		{"bob@example.com", []string{"users"}, false},
+	}
+
+	for _, c := range cases {
+		if got := upstream.IsAuthorized(c.email, c.groups); got != c.want {
+			t.Errorf("IsAuthorized(%s, %v) = %v, want %v", c.email, c.groups, got, c.want)
+		}
+	}
+}
+
+func TestUpstreamIsAuthorizedNoRulesDefersToGlobal(t *testing.T) {
+	upstream := &Upstream{ID: "app"}
+
+	if !upstream.IsAuthorized("anyone@example.com", nil) {
+		t.Error("expected IsAuthorized to return true when no per-upstream rules are configured")
+	}
+}
@@ -0,0 +1,84 @@
+package sessions
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/encryption"
+)
+
+// SessionState holds the information associated with a user's authentication
+// session that is persisted to a SessionStore between requests.
+type SessionState struct {
+	CreatedAt *time.Time `json:",omitempty"`
+	ExpiresOn *time.Time `json:",omitempty"`
+
+	AccessToken  string `json:",omitempty"`
+	IDToken      string `json:",omitempty"`
+	RefreshToken string `json:",omitempty"`
+
+	Email             string
+	User              string
+	PreferredUsername string
+	Groups            []string `json:",omitempty"`
+}
+
+// IsExpired returns whether the current session is expired.
+func (s *SessionState) IsExpired() bool {
+	return s.ExpiresOn != nil && !s.ExpiresOn.After(time.Now())
+}
+
+// EncodeSessionState encodes and encrypts the session with c, optionally
+// removing OAuth tokens first when minimal is true (used by session stores
+// that don't need to persist them, eg. the cookie store in
+// session-cookie-minimal mode). c is required: session state must never be
+// written to a cookie or a server-side store in plaintext.
+func (s *SessionState) EncodeSessionState(c encryption.Cipher, minimal bool) ([]byte, error) {
+	if c == nil {
+		return nil, fmt.Errorf("a cipher is required to encode session state")
+	}
+
+	state := s
+	if minimal {
+		state = &SessionState{
+			CreatedAt:         s.CreatedAt,
+			ExpiresOn:         s.ExpiresOn,
+			Email:             s.Email,
+			User:              s.User,
+			PreferredUsername: s.PreferredUsername,
+			Groups:            s.Groups,
+		}
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling session state: %w", err)
+	}
+
+	ciphertext, err := c.Encrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting session state: %w", err)
+	}
+	return ciphertext, nil
+}
+
+// DecodeSessionState decrypts and decodes a session previously encoded with
+// EncodeSessionState under the same cipher. It returns an error if the
+// value was tampered with or encoded under a different key.
+func DecodeSessionState(ciphertext []byte, c encryption.Cipher) (*SessionState, error) {
+	if c == nil {
+		return nil, fmt.Errorf("a cipher is required to decode session state")
+	}
+
+	data, err := c.Decrypt(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting session state: %w", err)
+	}
+
+	var s SessionState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("error unmarshalling session state: %w", err)
+	}
+	return &s, nil
+}
@@ -0,0 +1,86 @@
+package sessions
+
+import (
+	"testing"
+
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/encryption"
+)
+
+func TestEncodeDecodeSessionStateRoundTrip(t *testing.T) {
+	c, err := encryption.NewCipher([]byte("cookie-secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := &SessionState{
+		Email:        "jane@example.com",
+		User:         "jane",
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		Groups:       []string{"admins"},
+	}
+
+	encoded, err := s.EncodeSessionState(c, false)
+	if err != nil {
+		t.Fatalf("unexpected error encoding: %v", err)
+	}
+
+	decoded, err := DecodeSessionState(encoded, c)
+	if err != nil {
+		t.Fatalf("unexpected error decoding: %v", err)
+	}
+
+	if decoded.Email != s.Email || decoded.AccessToken != s.AccessToken {
+		t.Errorf("decoded session %+v does not match original %+v", decoded, s)
+	}
+}
+
+func TestEncodeSessionStateMinimalDropsTokens(t *testing.T) {
+	c, err := encryption.NewCipher([]byte("cookie-secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := &SessionState{Email: "jane@example.com", AccessToken: "access-token"}
+
+	encoded, err := s.EncodeSessionState(c, true)
+	if err != nil {
+		t.Fatalf("unexpected error encoding: %v", err)
+	}
+
+	decoded, err := DecodeSessionState(encoded, c)
+	if err != nil {
+		t.Fatalf("unexpected error decoding: %v", err)
+	}
+
+	if decoded.AccessToken != "" {
+		t.Errorf("expected AccessToken to be dropped in minimal mode, got %q", decoded.AccessToken)
+	}
+	if decoded.Email != s.Email {
+		t.Errorf("Email = %q, want %q", decoded.Email, s.Email)
+	}
+}
+
+func TestDecodeSessionStateRejectsTamperedCiphertext(t *testing.T) {
+	c, err := encryption.NewCipher([]byte("cookie-secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := &SessionState{Email: "jane@example.com"}
+	encoded, err := s.EncodeSessionState(c, false)
+	if err != nil {
+		t.Fatalf("unexpected error encoding: %v", err)
+	}
+	encoded[len(encoded)-1] ^= 0xFF
+
+	if _, err := DecodeSessionState(encoded, c); err == nil {
+		t.Fatal("expected an error decoding a tampered session")
+	}
+}
+
+func TestDecodeSessionStateRequiresCipher(t *testing.T) {
+	if _, err := DecodeSessionState([]byte("anything"), nil); err == nil {
+		t.Fatal("expected an error when no cipher is provided")
+	}
+}
@@ -0,0 +1,70 @@
+// Package encryption provides the symmetric encryption used to protect
+// session state before it is written to a cookie or a server-side session
+// store, so neither the client nor the backing store ever sees plaintext.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+)
+
+// Cipher encrypts and decrypts opaque byte values. Decrypt must reject any
+// value that was not produced by Encrypt with the same key, so tampered
+// session data is never returned to the caller.
+type Cipher interface {
+	Encrypt(value []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+type gcmCipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher derives an AES-256-GCM key from secret (via SHA-256, so any
+// length of cookie secret is accepted) and returns a Cipher that
+// encrypts-then-authenticates values with it.
+func NewCipher(secret []byte) (Cipher, error) {
+	key := sha256.Sum256(secret)
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("error creating aes cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating gcm: %w", err)
+	}
+
+	return &gcmCipher{aead: aead}, nil
+}
+
+// Encrypt seals value, prefixing the result with a freshly generated nonce.
+func (c *gcmCipher) Encrypt(value []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, value, nil), nil
+}
+
+// Decrypt opens a value produced by Encrypt, returning an error if
+// ciphertext is truncated or fails authentication (eg. it was tampered with
+// or encrypted under a different key).
+func (c *gcmCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, sealed := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	plain, err := c.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting value: %w", err)
+	}
+	return plain, nil
+}
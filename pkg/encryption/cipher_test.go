@@ -0,0 +1,63 @@
+package encryption
+
+import "testing"
+
+func TestCipherEncryptDecryptRoundTrip(t *testing.T) {
+	c, err := NewCipher([]byte("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ciphertext, err := c.Encrypt([]byte("plaintext"))
+	if err != nil {
+		t.Fatalf("unexpected error encrypting: %v", err)
+	}
+	if string(ciphertext) == "plaintext" {
+		t.Fatal("expected Encrypt to not return the plaintext verbatim")
+	}
+
+	plain, err := c.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatalf("unexpected error decrypting: %v", err)
+	}
+	if string(plain) != "plaintext" {
+		t.Errorf("Decrypt() = %q, want %q", plain, "plaintext")
+	}
+}
+
+func TestCipherDecryptRejectsTamperedValue(t *testing.T) {
+	c, err := NewCipher([]byte("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ciphertext, err := c.Encrypt([]byte("plaintext"))
+	if err != nil {
+		t.Fatalf("unexpected error encrypting: %v", err)
+	}
+	ciphertext[len(ciphertext)-1] ^= 0xFF
+
+	if _, err := c.Decrypt(ciphertext); err == nil {
+		t.Fatal("expected an error decrypting a tampered value")
+	}
+}
+
+func TestCipherDecryptRejectsWrongKey(t *testing.T) {
+	a, err := NewCipher([]byte("secret-a"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := NewCipher([]byte("secret-b"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ciphertext, err := a.Encrypt([]byte("plaintext"))
+	if err != nil {
+		t.Fatalf("unexpected error encrypting: %v", err)
+	}
+
+	if _, err := b.Decrypt(ciphertext); err == nil {
+		t.Fatal("expected an error decrypting a value encrypted under a different key")
+	}
+}
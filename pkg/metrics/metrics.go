@@ -0,0 +1,107 @@
+// Package metrics exposes the Prometheus counters and histograms oauth2-proxy
+// reports about its own operation: authentication outcomes, session store
+// latency, upstream proxy latency, token refreshes and cookie decode errors.
+package metrics
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// AuthTotal counts authentication attempts by provider and outcome
+	// ("success", "failure", "error").
+	AuthTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "oauth2_proxy_auth_total",
+		Help: "Total number of authentication attempts by provider and outcome",
+	}, []string{"provider", "outcome"})
+
+	// SessionStoreDuration observes the latency of session store
+	// operations ("load", "save", "clear", "lock") in seconds.
+	SessionStoreDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "oauth2_proxy_session_store_duration_seconds",
+		Help:    "Latency of session store operations in seconds",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	// UpstreamProxyDuration observes the latency of proxied upstream
+	// requests in seconds, labelled by upstream ID.
+	UpstreamProxyDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "oauth2_proxy_upstream_proxy_duration_seconds",
+		Help:    "Latency of proxied upstream requests in seconds",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"upstream"})
+
+	// TokenRefreshTotal counts access token refresh attempts by outcome
+	// ("success", "failure").
+	TokenRefreshTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "oauth2_proxy_token_refresh_total",
+		Help: "Total number of access token refresh attempts by outcome",
+	}, []string{"outcome"})
+
+	// CookieDecodeErrorsTotal counts failures to decode a session cookie.
+	CookieDecodeErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "oauth2_proxy_cookie_decode_errors_total",
+		Help: "Total number of session cookie decode errors",
+	})
+)
+
+// RecordAuthOutcome increments AuthTotal for provider/outcome.
+func RecordAuthOutcome(provider, outcome string) {
+	AuthTotal.WithLabelValues(provider, outcome).Inc()
+}
+
+// RecordSessionStoreOperation observes the duration of a session store
+// operation.
+func RecordSessionStoreOperation(operation string, duration time.Duration) {
+	SessionStoreDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// RecordUpstreamProxy observes the duration of a proxied upstream request.
+func RecordUpstreamProxy(upstream string, duration time.Duration) {
+	UpstreamProxyDuration.WithLabelValues(upstream).Observe(duration.Seconds())
+}
+
+// RecordTokenRefresh increments TokenRefreshTotal for outcome.
+func RecordTokenRefresh(outcome string) {
+	TokenRefreshTotal.WithLabelValues(outcome).Inc()
+}
+
+// RecordCookieDecodeError increments CookieDecodeErrorsTotal.
+func RecordCookieDecodeError() {
+	CookieDecodeErrorsTotal.Inc()
+}
+
+// NewServer builds the HTTP server that exposes the metrics endpoint
+// described by opts. It returns nil, nil when opts.BindAddress is empty, so
+// callers can skip serving metrics entirely.
+func NewServer(opts options.MetricsOptions) (*http.Server, error) {
+	if opts.BindAddress == "" {
+		return nil, nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(opts.Path, promhttp.Handler())
+
+	server := &http.Server{
+		Addr:    opts.BindAddress,
+		Handler: mux,
+	}
+
+	if opts.TLSCertFile != "" || opts.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.TLSCertFile, opts.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading metrics TLS certificate: %w", err)
+		}
+		server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+
+	return server, nil
+}
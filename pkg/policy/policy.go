@@ -0,0 +1,119 @@
+// Package policy evaluates per-request authorization decisions against an
+// operator-supplied Rego policy, as an expressive alternative to the
+// collection of ad-hoc allowlist flags (email domains, whitelist domains,
+// provider group checks).
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// SessionInput is the subset of session state exposed to the policy.
+type SessionInput struct {
+	Email             string                 `json:"email"`
+	Groups            []string               `json:"groups"`
+	PreferredUsername string                 `json:"preferred_username"`
+	AccessTokenClaims map[string]interface{} `json:"access_token_claims,omitempty"`
+}
+
+// RequestInput is the subset of the HTTP request exposed to the policy.
+type RequestInput struct {
+	Method  string              `json:"method"`
+	Path    string              `json:"path"`
+	Headers map[string][]string `json:"headers"`
+}
+
+// Input is the decision input handed to the Rego query: the session making
+// the request, the request itself, and which upstream it is destined for.
+type Input struct {
+	Session  SessionInput `json:"session"`
+	Request  RequestInput `json:"request"`
+	Upstream string       `json:"upstream"`
+}
+
+// Decision is the result of evaluating a policy: whether the request is
+// allowed, and any response headers the policy wants set.
+type Decision struct {
+	Allow           bool                `json:"allow"`
+	ResponseHeaders map[string][]string `json:"response_headers"`
+}
+
+// Evaluator evaluates the decision input against a policy that was compiled
+// and prepared once at startup.
+type Evaluator struct {
+	query rego.PreparedEvalQuery
+}
+
+// NewEvaluator compiles opts.PolicyFile or opts.Policy and prepares it for
+// repeated evaluation. It returns a nil Evaluator, nil error when no policy
+// is configured, so callers can treat that as "policy evaluation disabled".
+func NewEvaluator(ctx context.Context, opts options.AuthorizationPolicyOptions) (*Evaluator, error) {
+	if opts.PolicyFile == "" && opts.Policy == "" {
+		return nil, nil
+	}
+	if opts.PolicyFile != "" && opts.Policy != "" {
+		return nil, fmt.Errorf("authorization-policy-file and authorization-policy are mutually exclusive")
+	}
+
+	regoOpts := []func(*rego.Rego){rego.Query(opts.Query)}
+	if opts.PolicyFile != "" {
+		regoOpts = append(regoOpts, rego.Load([]string{opts.PolicyFile}, nil))
+	} else {
+		regoOpts = append(regoOpts, rego.Module("oauth2-proxy.rego", opts.Policy))
+	}
+
+	query, err := rego.New(regoOpts...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling authorization policy: %w", err)
+	}
+
+	return &Evaluator{query: query}, nil
+}
+
+// Evaluate runs the prepared query against input and decodes the result
+// into a Decision. A policy that doesn't set `allow` is treated as denying
+// the request.
+func (e *Evaluator) Evaluate(ctx context.Context, input Input) (*Decision, error) {
+	results, err := e.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating authorization policy: %w", err)
+	}
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return &Decision{Allow: false}, nil
+	}
+
+	return decodeDecision(results[0].Expressions[0].Value)
+}
+
+func decodeDecision(value interface{}) (*Decision, error) {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("authorization policy result must be an object, got %T", value)
+	}
+
+	decision := &Decision{}
+	if allow, ok := m["allow"].(bool); ok {
+		decision.Allow = allow
+	}
+
+	if headers, ok := m["response_headers"].(map[string]interface{}); ok {
+		decision.ResponseHeaders = make(map[string][]string, len(headers))
+		for name, rawValues := range headers {
+			values, ok := rawValues.([]interface{})
+			if !ok {
+				continue
+			}
+			for _, v := range values {
+				if s, ok := v.(string); ok {
+					decision.ResponseHeaders[name] = append(decision.ResponseHeaders[name], s)
+				}
+			}
+		}
+	}
+
+	return decision, nil
+}
@@ -0,0 +1,42 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeDecision(t *testing.T) {
+	value := map[string]interface{}{
+		"allow": true,
+		"response_headers": map[string]interface{}{
+			"X-Foo": []interface{}{"bar", "baz"},
+		},
+	}
+
+	decision, err := decodeDecision(value)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decision.Allow {
+		t.Error("expected decision to allow the request")
+	}
+	if want := map[string][]string{"X-Foo": {"bar", "baz"}}; !reflect.DeepEqual(decision.ResponseHeaders, want) {
+		t.Errorf("ResponseHeaders = %v, want %v", decision.ResponseHeaders, want)
+	}
+}
+
+func TestDecodeDecisionDeniesByDefault(t *testing.T) {
+	decision, err := decodeDecision(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Allow {
+		t.Error("expected decision to deny the request when allow is unset")
+	}
+}
+
+func TestDecodeDecisionRejectsNonObject(t *testing.T) {
+	if _, err := decodeDecision("not-an-object"); err == nil {
+		t.Error("expected an error for a non-object policy result")
+	}
+}
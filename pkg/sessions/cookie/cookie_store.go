@@ -0,0 +1,80 @@
+package cookie
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+	sessionsapi "github.com/oauth2-proxy/oauth2-proxy/pkg/apis/sessions"
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/encryption"
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/sessions"
+)
+
+func init() {
+	sessions.RegisterStoreFactory(options.CookieSessionStoreType, NewCookieSessionStore)
+}
+
+// sessionStore persists the full encoded, encrypted SessionState in the
+// client's cookie. It requires no external infrastructure, at the cost of a
+// cookie size limit (see --session-cookie-minimal to keep the encoded state
+// small).
+type sessionStore struct {
+	cookieOpts *options.Cookie
+	cipher     encryption.Cipher
+	minimal    bool
+}
+
+// NewCookieSessionStore creates a SessionStore backed entirely by the
+// client's session cookie.
+func NewCookieSessionStore(opts *options.SessionOptions, cookieOpts *options.Cookie) (sessions.SessionStore, error) {
+	c, err := encryption.NewCipher([]byte(cookieOpts.Secret))
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	return &sessionStore{cookieOpts: cookieOpts, cipher: c, minimal: opts.Cookie.Minimal}, nil
+}
+
+func (s *sessionStore) Save(rw http.ResponseWriter, req *http.Request, ss *sessionsapi.SessionState) error {
+	value, err := ss.EncodeSessionState(s.cipher, s.minimal)
+	if err != nil {
+		return fmt.Errorf("error encoding session state: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(value)
+	http.SetCookie(rw, sessions.MakeCookie(req, s.cookieOpts, encoded))
+	return nil
+}
+
+func (s *sessionStore) Load(req *http.Request) (*sessionsapi.SessionState, error) {
+	c, err := req.Cookie(s.cookieOpts.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving session cookie: %w", err)
+	}
+
+	value, err := base64.RawURLEncoding.DecodeString(c.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding session cookie: %w", err)
+	}
+
+	return sessionsapi.DecodeSessionState(value, s.cipher)
+}
+
+func (s *sessionStore) Clear(rw http.ResponseWriter, req *http.Request) error {
+	sessions.ClearTicketCookie(rw, req, s.cookieOpts)
+	return nil
+}
+
+// Lock is a no-op for the cookie store: there is no shared backing store to
+// coordinate, so each replica simply refreshes and re-sets its own cookie.
+func (s *sessionStore) Lock(_ *http.Request) (sessions.Lock, error) {
+	return noopLock{}, nil
+}
+
+type noopLock struct{}
+
+func (noopLock) Obtain(context.Context, time.Duration) error  { return nil }
+func (noopLock) Peek(context.Context) (bool, error)           { return false, nil }
+func (noopLock) Refresh(context.Context, time.Duration) error { return nil }
+func (noopLock) Release(context.Context) error                { return nil }
@@ -0,0 +1,28 @@
+package sessions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+)
+
+// MakeCookie builds the session cookie carrying value (either the ticket ID
+// for server-side stores, or the full encoded session for the cookie store).
+func MakeCookie(req *http.Request, cookieOpts *options.Cookie, value string) *http.Cookie {
+	domain := cookieOpts.Domain
+	if domain == "" {
+		domain = req.Host
+	}
+
+	return &http.Cookie{
+		Name:     cookieOpts.Name,
+		Value:    value,
+		Path:     cookieOpts.Path,
+		Domain:   domain,
+		Expires:  time.Now().Add(cookieOpts.Expire),
+		HttpOnly: cookieOpts.HTTPOnly,
+		Secure:   cookieOpts.Secure,
+		SameSite: cookieOpts.SameSite,
+	}
+}
@@ -0,0 +1,158 @@
+package dynamodb
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+	sessionsapi "github.com/oauth2-proxy/oauth2-proxy/pkg/apis/sessions"
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/encryption"
+	oasessions "github.com/oauth2-proxy/oauth2-proxy/pkg/sessions"
+)
+
+func init() {
+	oasessions.RegisterStoreFactory(options.DynamoDBSessionStoreType, NewDynamoDBSessionStore)
+}
+
+// item is the shape of a row in the DynamoDB sessions table. ID is the hash
+// key (the session ticket). ExpiresAt is exposed as the table's configured
+// TTL attribute so DynamoDB reaps expired sessions for us.
+type item struct {
+	ID        string `dynamodbav:"id"`
+	Value     []byte `dynamodbav:"value"`
+	ExpiresAt int64  `dynamodbav:"expires_at"`
+}
+
+// sessionStore persists sessions as a ticket, with the encoded SessionState
+// held server-side in a DynamoDB table, following the same semantics as the
+// Redis store: small cookie, session lock for concurrent refreshes.
+type sessionStore struct {
+	client     *dynamodb.DynamoDB
+	table      string
+	ttl        time.Duration
+	cookieOpts *options.Cookie
+	cipher     encryption.Cipher
+	minimal    bool
+}
+
+// NewDynamoDBSessionStore creates a SessionStore backed by DynamoDB, selected
+// by DynamoDBStoreOptions.
+func NewDynamoDBSessionStore(opts *options.SessionOptions, cookieOpts *options.Cookie) (oasessions.SessionStore, error) {
+	if opts.DynamoDB.Table == "" {
+		return nil, fmt.Errorf("dynamodb-table must be set when session-store-type is dynamodb")
+	}
+
+	cfg := aws.NewConfig()
+	if opts.DynamoDB.Region != "" {
+		cfg = cfg.WithRegion(opts.DynamoDB.Region)
+	}
+	if opts.DynamoDB.Endpoint != "" {
+		cfg = cfg.WithEndpoint(opts.DynamoDB.Endpoint)
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating aws session: %w", err)
+	}
+
+	c, err := encryption.NewCipher([]byte(cookieOpts.Secret))
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+
+	return &sessionStore{
+		client:     dynamodb.New(sess),
+		table:      opts.DynamoDB.Table,
+		ttl:        time.Duration(opts.DynamoDB.TTL) * time.Second,
+		cookieOpts: cookieOpts,
+		cipher:     c,
+		minimal:    opts.Cookie.Minimal,
+	}, nil
+}
+
+func (s *sessionStore) Save(rw http.ResponseWriter, req *http.Request, ss *sessionsapi.SessionState) error {
+	ticket, err := oasessions.TicketForSave(req, s.cookieOpts)
+	if err != nil {
+		return err
+	}
+
+	value, err := ss.EncodeSessionState(s.cipher, s.minimal)
+	if err != nil {
+		return fmt.Errorf("error encoding session state: %w", err)
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item{
+		ID:        ticket.ID,
+		Value:     value,
+		ExpiresAt: time.Now().Add(s.ttl).Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("error marshalling session item: %w", err)
+	}
+
+	if _, err := s.client.PutItemWithContext(req.Context(), &dynamodb.PutItemInput{
+		TableName: aws.String(s.table),
+		Item:      av,
+	}); err != nil {
+		return fmt.Errorf("error saving session in dynamodb: %w", err)
+	}
+
+	ticket.SetCookie(rw, req, s.cookieOpts)
+	return nil
+}
+
+func (s *sessionStore) Load(req *http.Request) (*sessionsapi.SessionState, error) {
+	ticket, err := oasessions.TicketFromRequest(req, s.cookieOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.client.GetItemWithContext(req.Context(), &dynamodb.GetItemInput{
+		TableName: aws.String(s.table),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String(ticket.ID)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error loading session from dynamodb: %w", err)
+	}
+	if out.Item == nil {
+		return nil, fmt.Errorf("session not found for ticket %q", ticket.ID)
+	}
+
+	var i item
+	if err := dynamodbattribute.UnmarshalMap(out.Item, &i); err != nil {
+		return nil, fmt.Errorf("error unmarshalling session item: %w", err)
+	}
+
+	return sessionsapi.DecodeSessionState(i.Value, s.cipher)
+}
+
+func (s *sessionStore) Clear(rw http.ResponseWriter, req *http.Request) error {
+	if ticket, err := oasessions.TicketFromRequest(req, s.cookieOpts); err == nil {
+		if _, delErr := s.client.DeleteItemWithContext(req.Context(), &dynamodb.DeleteItemInput{
+			TableName: aws.String(s.table),
+			Key: map[string]*dynamodb.AttributeValue{
+				"id": {S: aws.String(ticket.ID)},
+			},
+		}); delErr != nil {
+			return fmt.Errorf("error removing session from dynamodb: %w", delErr)
+		}
+	}
+	oasessions.ClearTicketCookie(rw, req, s.cookieOpts)
+	return nil
+}
+
+func (s *sessionStore) Lock(req *http.Request) (oasessions.Lock, error) {
+	ticket, err := oasessions.TicketFromRequest(req, s.cookieOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &lock{client: s.client, table: s.table, key: ticket.ID + ".lock"}, nil
+}
@@ -0,0 +1,80 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// lock is a distributed Lock implemented as a row in the same DynamoDB
+// table, guarded by a conditional PutItem so only one replica can create it
+// while it hasn't yet expired.
+type lock struct {
+	client *dynamodb.DynamoDB
+	table  string
+	key    string
+}
+
+func (l *lock) Obtain(ctx context.Context, expiration time.Duration) error {
+	_, err := l.client.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(l.table),
+		Item: map[string]*dynamodb.AttributeValue{
+			"id":         {S: aws.String(l.key)},
+			"expires_at": {N: aws.String(fmt.Sprintf("%d", time.Now().Add(expiration).Unix()))},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(id) OR expires_at < :now"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":now": {N: aws.String(fmt.Sprintf("%d", time.Now().Unix()))},
+		},
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return fmt.Errorf("lock %q is already held", l.key)
+		}
+		return fmt.Errorf("error obtaining dynamodb lock: %w", err)
+	}
+	return nil
+}
+
+func (l *lock) Peek(ctx context.Context) (bool, error) {
+	out, err := l.client.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(l.table),
+		Key:            map[string]*dynamodb.AttributeValue{"id": {S: aws.String(l.key)}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return false, fmt.Errorf("error checking dynamodb lock: %w", err)
+	}
+	return out.Item != nil, nil
+}
+
+func (l *lock) Refresh(ctx context.Context, expiration time.Duration) error {
+	_, err := l.client.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(l.table),
+		Key:              map[string]*dynamodb.AttributeValue{"id": {S: aws.String(l.key)}},
+		UpdateExpression: aws.String("SET expires_at = :expires"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":expires": {N: aws.String(fmt.Sprintf("%d", time.Now().Add(expiration).Unix()))},
+		},
+		ConditionExpression: aws.String("attribute_exists(id)"),
+	})
+	if err != nil {
+		return fmt.Errorf("error refreshing dynamodb lock: %w", err)
+	}
+	return nil
+}
+
+func (l *lock) Release(ctx context.Context) error {
+	_, err := l.client.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(l.table),
+		Key:       map[string]*dynamodb.AttributeValue{"id": {S: aws.String(l.key)}},
+	})
+	if err != nil {
+		return fmt.Errorf("error releasing dynamodb lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package sessions
+
+import (
+	"fmt"
+
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+)
+
+// StoreFactory builds a SessionStore from the given SessionOptions and
+// Cookie options. Each backend registers itself under the
+// options.SessionOptions.Type value it implements by calling
+// RegisterStoreFactory from an init() function in its package.
+type StoreFactory func(opts *options.SessionOptions, cookieOpts *options.Cookie) (SessionStore, error)
+
+var storeFactories = map[string]StoreFactory{}
+
+// RegisterStoreFactory makes a SessionStore backend available under the
+// given --session-store-type name. It panics if the name is already
+// registered, since that indicates two backends were compiled in under
+// the same name.
+func RegisterStoreFactory(name string, factory StoreFactory) {
+	if _, exists := storeFactories[name]; exists {
+		panic(fmt.Sprintf("session store factory already registered for type %q", name))
+	}
+	storeFactories[name] = factory
+}
+
+// NewSessionStore creates a SessionStore from the provided configuration,
+// dispatching to the backend registered for opts.Type.
+func NewSessionStore(opts *options.SessionOptions, cookieOpts *options.Cookie) (SessionStore, error) {
+	factory, ok := storeFactories[opts.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown session-store-type %q", opts.Type)
+	}
+	return factory(opts, cookieOpts)
+}
@@ -0,0 +1,56 @@
+package memcached
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// lock is a distributed Lock implemented with memcached's Add operation,
+// which only succeeds if the key does not already exist.
+type lock struct {
+	client *memcache.Client
+	key    string
+}
+
+func (l *lock) Obtain(_ context.Context, expiration time.Duration) error {
+	err := l.client.Add(&memcache.Item{
+		Key:        l.key,
+		Value:      []byte("locked"),
+		Expiration: int32(expiration.Seconds()),
+	})
+	if err == memcache.ErrNotStored {
+		return fmt.Errorf("lock %q is already held", l.key)
+	}
+	if err != nil {
+		return fmt.Errorf("error obtaining memcached lock: %w", err)
+	}
+	return nil
+}
+
+func (l *lock) Peek(_ context.Context) (bool, error) {
+	_, err := l.client.Get(l.key)
+	if err == memcache.ErrCacheMiss {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error checking memcached lock: %w", err)
+	}
+	return true, nil
+}
+
+func (l *lock) Refresh(_ context.Context, expiration time.Duration) error {
+	if err := l.client.Touch(l.key, int32(expiration.Seconds())); err != nil {
+		return fmt.Errorf("error refreshing memcached lock: %w", err)
+	}
+	return nil
+}
+
+func (l *lock) Release(_ context.Context) error {
+	if err := l.client.Delete(l.key); err != nil && err != memcache.ErrCacheMiss {
+		return fmt.Errorf("error releasing memcached lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package memcached
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+	sessionsapi "github.com/oauth2-proxy/oauth2-proxy/pkg/apis/sessions"
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/encryption"
+	oasessions "github.com/oauth2-proxy/oauth2-proxy/pkg/sessions"
+)
+
+func init() {
+	oasessions.RegisterStoreFactory(options.MemcachedSessionStoreType, NewMemcachedSessionStore)
+}
+
+// sessionStore persists sessions as a ticket, with the encoded SessionState
+// held server-side in Memcached, following the same semantics as the Redis
+// store: small cookie, session lock for concurrent refreshes.
+type sessionStore struct {
+	client     *memcache.Client
+	ttl        time.Duration
+	cookieOpts *options.Cookie
+	cipher     encryption.Cipher
+	minimal    bool
+}
+
+// NewMemcachedSessionStore creates a SessionStore backed by one or more
+// Memcached servers, selected by MemcachedStoreOptions.
+func NewMemcachedSessionStore(opts *options.SessionOptions, cookieOpts *options.Cookie) (oasessions.SessionStore, error) {
+	if len(opts.Memcached.Servers) == 0 {
+		return nil, fmt.Errorf("memcached-server must be set when session-store-type is memcached")
+	}
+
+	c, err := encryption.NewCipher([]byte(cookieOpts.Secret))
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+
+	return &sessionStore{
+		client:     memcache.New(opts.Memcached.Servers...),
+		ttl:        time.Duration(opts.Memcached.TTL) * time.Second,
+		cookieOpts: cookieOpts,
+		cipher:     c,
+		minimal:    opts.Cookie.Minimal,
+	}, nil
+}
+
+func (s *sessionStore) Save(rw http.ResponseWriter, req *http.Request, ss *sessionsapi.SessionState) error {
+	ticket, err := oasessions.TicketForSave(req, s.cookieOpts)
+	if err != nil {
+		return err
+	}
+
+	value, err := ss.EncodeSessionState(s.cipher, s.minimal)
+	if err != nil {
+		return fmt.Errorf("error encoding session state: %w", err)
+	}
+
+	if err := s.client.Set(&memcache.Item{
+		Key:        ticket.ID,
+		Value:      value,
+		Expiration: int32(s.ttl.Seconds()),
+	}); err != nil {
+		return fmt.Errorf("error saving session in memcached: %w", err)
+	}
+
+	ticket.SetCookie(rw, req, s.cookieOpts)
+	return nil
+}
+
+func (s *sessionStore) Load(req *http.Request) (*sessionsapi.SessionState, error) {
+	ticket, err := oasessions.TicketFromRequest(req, s.cookieOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := s.client.Get(ticket.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading session from memcached: %w", err)
+	}
+
+	return sessionsapi.DecodeSessionState(i.Value, s.cipher)
+}
+
+func (s *sessionStore) Clear(rw http.ResponseWriter, req *http.Request) error {
+	if ticket, err := oasessions.TicketFromRequest(req, s.cookieOpts); err == nil {
+		if delErr := s.client.Delete(ticket.ID); delErr != nil && delErr != memcache.ErrCacheMiss {
+			return fmt.Errorf("error removing session from memcached: %w", delErr)
+		}
+	}
+	oasessions.ClearTicketCookie(rw, req, s.cookieOpts)
+	return nil
+}
+
+func (s *sessionStore) Lock(req *http.Request) (oasessions.Lock, error) {
+	ticket, err := oasessions.TicketFromRequest(req, s.cookieOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &lock{client: s.client, key: ticket.ID + ".lock"}, nil
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+)
+
+// newRedisClient builds a redis.UniversalClient for standalone, sentinel, or
+// cluster mode Redis, based on which options are set.
+func newRedisClient(opts options.RedisStoreOptions) (redis.UniversalClient, error) {
+	tlsConfig, err := redisTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case opts.UseCluster:
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:     opts.ClusterConnectionURLs,
+			Password:  opts.Password,
+			TLSConfig: tlsConfig,
+		}), nil
+	case opts.UseSentinel:
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       opts.SentinelMasterName,
+			SentinelAddrs:    opts.SentinelConnectionURLs,
+			SentinelPassword: opts.SentinelPassword,
+			Password:         opts.Password,
+			TLSConfig:        tlsConfig,
+		}), nil
+	default:
+		connOpts, err := redis.ParseURL(opts.ConnectionURL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing redis-connection-url: %w", err)
+		}
+		if opts.Password != "" {
+			connOpts.Password = opts.Password
+		}
+		connOpts.TLSConfig = tlsConfig
+		return redis.NewClient(connOpts), nil
+	}
+}
+
+func redisTLSConfig(opts options.RedisStoreOptions) (*tls.Config, error) {
+	if opts.CAPath == "" && !opts.InsecureSkipTLSVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: opts.InsecureSkipTLSVerify} //nolint:gosec // explicit opt-in flag
+
+	if opts.CAPath != "" {
+		ca, err := ioutil.ReadFile(opts.CAPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading redis-ca-path: %w", err)
+		}
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(ca)
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
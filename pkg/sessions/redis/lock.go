@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+// lock is a distributed Lock implemented with a Redis key, set with NX so
+// only one oauth2-proxy replica can hold it at a time. It is used to
+// serialize concurrent refreshes of the same session.
+type lock struct {
+	client redis.UniversalClient
+	key    string
+}
+
+// ctx is accepted to satisfy the Lock interface, but go-redis v7's
+// UniversalClient has no per-call context plumbing (only the concrete
+// clients' WithContext, which UniversalClient does not expose) so it is
+// unused here.
+
+func (l *lock) Obtain(_ context.Context, expiration time.Duration) error {
+	ok, err := l.client.SetNX(l.key, "locked", expiration).Result()
+	if err != nil {
+		return fmt.Errorf("error obtaining redis lock: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("lock %q is already held", l.key)
+	}
+	return nil
+}
+
+func (l *lock) Peek(_ context.Context) (bool, error) {
+	n, err := l.client.Exists(l.key).Result()
+	if err != nil {
+		return false, fmt.Errorf("error checking redis lock: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (l *lock) Refresh(_ context.Context, expiration time.Duration) error {
+	ok, err := l.client.Expire(l.key, expiration).Result()
+	if err != nil {
+		return fmt.Errorf("error refreshing redis lock: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("lock %q is not held, cannot refresh", l.key)
+	}
+	return nil
+}
+
+func (l *lock) Release(_ context.Context) error {
+	if err := l.client.Del(l.key).Err(); err != nil {
+		return fmt.Errorf("error releasing redis lock: %w", err)
+	}
+	return nil
+}
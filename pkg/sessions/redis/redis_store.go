@@ -0,0 +1,94 @@
+package redis
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+	sessionsapi "github.com/oauth2-proxy/oauth2-proxy/pkg/apis/sessions"
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/encryption"
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/sessions"
+)
+
+func init() {
+	sessions.RegisterStoreFactory(options.RedisSessionStoreType, NewRedisSessionStore)
+}
+
+// sessionStore persists sessions as a ticket (a random ID carried in the
+// client's cookie) with the encoded SessionState held server-side in Redis.
+// This keeps the cookie small and lets a session be revoked by deleting the
+// corresponding Redis key.
+type sessionStore struct {
+	client     redis.UniversalClient
+	cookieOpts *options.Cookie
+	cipher     encryption.Cipher
+	minimal    bool
+}
+
+// NewRedisSessionStore creates a SessionStore backed by standalone, sentinel
+// or cluster Redis, selected by RedisStoreOptions.
+func NewRedisSessionStore(opts *options.SessionOptions, cookieOpts *options.Cookie) (sessions.SessionStore, error) {
+	client, err := newRedisClient(opts.Redis)
+	if err != nil {
+		return nil, fmt.Errorf("error constructing redis client: %w", err)
+	}
+
+	c, err := encryption.NewCipher([]byte(cookieOpts.Secret))
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+
+	return &sessionStore{client: client, cookieOpts: cookieOpts, cipher: c, minimal: opts.Cookie.Minimal}, nil
+}
+
+func (s *sessionStore) Save(rw http.ResponseWriter, req *http.Request, ss *sessionsapi.SessionState) error {
+	ticket, err := sessions.TicketForSave(req, s.cookieOpts)
+	if err != nil {
+		return err
+	}
+
+	value, err := ss.EncodeSessionState(s.cipher, s.minimal)
+	if err != nil {
+		return fmt.Errorf("error encoding session state: %w", err)
+	}
+
+	if err := s.client.Set(ticket.ID, value, s.cookieOpts.Expire).Err(); err != nil {
+		return fmt.Errorf("error saving session in redis: %w", err)
+	}
+
+	ticket.SetCookie(rw, req, s.cookieOpts)
+	return nil
+}
+
+func (s *sessionStore) Load(req *http.Request) (*sessionsapi.SessionState, error) {
+	ticket, err := sessions.TicketFromRequest(req, s.cookieOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := s.client.Get(ticket.ID).Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("error loading session from redis: %w", err)
+	}
+
+	return sessionsapi.DecodeSessionState(value, s.cipher)
+}
+
+func (s *sessionStore) Clear(rw http.ResponseWriter, req *http.Request) error {
+	if ticket, err := sessions.TicketFromRequest(req, s.cookieOpts); err == nil {
+		if delErr := s.client.Del(ticket.ID).Err(); delErr != nil && delErr != redis.Nil {
+			return fmt.Errorf("error removing session from redis: %w", delErr)
+		}
+	}
+	sessions.ClearTicketCookie(rw, req, s.cookieOpts)
+	return nil
+}
+
+func (s *sessionStore) Lock(req *http.Request) (sessions.Lock, error) {
+	ticket, err := sessions.TicketFromRequest(req, s.cookieOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &lock{client: s.client, key: ticket.ID + ".lock"}, nil
+}
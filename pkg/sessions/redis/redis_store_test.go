@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+	sessionsapi "github.com/oauth2-proxy/oauth2-proxy/pkg/apis/sessions"
+)
+
+func newTestStore(t *testing.T) *sessionStore {
+	t.Helper()
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("unexpected error starting miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	cookieOpts := &options.Cookie{
+		Name:   "_oauth2_proxy",
+		Path:   "/",
+		Expire: time.Hour,
+		Secret: "0123456789abcdef0123456789abcdef",
+	}
+
+	store, err := NewRedisSessionStore(&options.SessionOptions{
+		Redis: options.RedisStoreOptions{ConnectionURL: "redis://" + mr.Addr()},
+	}, cookieOpts)
+	if err != nil {
+		t.Fatalf("unexpected error creating redis session store: %v", err)
+	}
+
+	return store.(*sessionStore)
+}
+
+func TestRedisSessionStoreSaveLoadClear(t *testing.T) {
+	store := newTestStore(t)
+
+	ss := &sessionsapi.SessionState{Email: "jane@example.com", User: "jane"}
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := store.Save(rw, req, ss); err != nil {
+		t.Fatalf("unexpected error saving session: %v", err)
+	}
+
+	loadReq := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range rw.Result().Cookies() {
+		loadReq.AddCookie(c)
+	}
+
+	loaded, err := store.Load(loadReq)
+	if err != nil {
+		t.Fatalf("unexpected error loading session: %v", err)
+	}
+	if loaded.Email != ss.Email || loaded.User != ss.User {
+		t.Errorf("loaded session %+v does not match saved session %+v", loaded, ss)
+	}
+
+	clearRW := httptest.NewRecorder()
+	if err := store.Clear(clearRW, loadReq); err != nil {
+		t.Fatalf("unexpected error clearing session: %v", err)
+	}
+
+	if _, err := store.Load(loadReq); err == nil {
+		t.Fatal("expected an error loading a cleared session")
+	}
+}
+
+func TestRedisSessionStoreSaveReusesExistingTicket(t *testing.T) {
+	store := newTestStore(t)
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := store.Save(rw, req, &sessionsapi.SessionState{Email: "jane@example.com"}); err != nil {
+		t.Fatalf("unexpected error saving session: %v", err)
+	}
+
+	refreshReq := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range rw.Result().Cookies() {
+		refreshReq.AddCookie(c)
+	}
+	firstTicketCookie := rw.Result().Cookies()[0].Value
+
+	refreshRW := httptest.NewRecorder()
+	refreshed := &sessionsapi.SessionState{Email: "jane@example.com", AccessToken: "refreshed-token"}
+	if err := store.Save(refreshRW, refreshReq, refreshed); err != nil {
+		t.Fatalf("unexpected error saving refreshed session: %v", err)
+	}
+
+	refreshCookies := refreshRW.Result().Cookies()
+	if len(refreshCookies) != 1 || refreshCookies[0].Value != firstTicketCookie {
+		t.Fatalf("expected the refresh Save to reuse the existing ticket %q, got %+v", firstTicketCookie, refreshCookies)
+	}
+
+	loaded, err := store.Load(refreshReq)
+	if err != nil {
+		t.Fatalf("unexpected error loading session under the reused ticket: %v", err)
+	}
+	if loaded.AccessToken != refreshed.AccessToken {
+		t.Errorf("AccessToken = %q, want %q (refresh was not visible under the original ticket)", loaded.AccessToken, refreshed.AccessToken)
+	}
+}
+
+func TestRedisSessionStoreLock(t *testing.T) {
+	store := newTestStore(t)
+
+	ss := &sessionsapi.SessionState{Email: "jane@example.com"}
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := store.Save(rw, req, ss); err != nil {
+		t.Fatalf("unexpected error saving session: %v", err)
+	}
+
+	lockReq := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range rw.Result().Cookies() {
+		lockReq.AddCookie(c)
+	}
+
+	l, err := store.Lock(lockReq)
+	if err != nil {
+		t.Fatalf("unexpected error obtaining lock: %v", err)
+	}
+
+	ctx := lockReq.Context()
+	if err := l.Obtain(ctx, time.Second); err != nil {
+		t.Fatalf("unexpected error acquiring lock: %v", err)
+	}
+
+	l2, err := store.Lock(lockReq)
+	if err != nil {
+		t.Fatalf("unexpected error obtaining second lock handle: %v", err)
+	}
+	if err := l2.Obtain(ctx, time.Second); err == nil {
+		t.Fatal("expected second lock acquisition to fail while the first is held")
+	}
+
+	if err := l.Release(ctx); err != nil {
+		t.Fatalf("unexpected error releasing lock: %v", err)
+	}
+
+	if err := l2.Obtain(ctx, time.Second); err != nil {
+		t.Fatalf("expected lock acquisition to succeed after release: %v", err)
+	}
+}
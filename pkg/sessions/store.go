@@ -0,0 +1,44 @@
+package sessions
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	sessionsapi "github.com/oauth2-proxy/oauth2-proxy/pkg/apis/sessions"
+)
+
+// SessionStore is the interface that any session storage backend must
+// implement in order to persist a user's SessionState between requests.
+//
+// Save and Load are responsible for getting the SessionState in and out of
+// whatever is carried on the request/response (typically a cookie holding
+// either the encoded session itself, or a ticket referencing it in the
+// backing store). Clear removes the session from both the backing store
+// and the client.
+type SessionStore interface {
+	Save(rw http.ResponseWriter, req *http.Request, s *sessionsapi.SessionState) error
+	Load(req *http.Request) (*sessionsapi.SessionState, error)
+	Clear(rw http.ResponseWriter, req *http.Request) error
+
+	// Lock returns a distributed lock keyed on the session carried by req.
+	// It is used to serialize concurrent refreshes of the same session
+	// across multiple oauth2-proxy replicas. Stores that are inherently
+	// single-writer (eg. the cookie store) may return a no-op Lock.
+	Lock(req *http.Request) (Lock, error)
+}
+
+// Lock is a distributed mutual exclusion lock associated with a single
+// session. It is obtained for the duration of a token refresh so that only
+// one replica performs the refresh while others wait for the result.
+type Lock interface {
+	// Obtain acquires the lock, blocking or returning an error if it is
+	// already held elsewhere and does not clear within expiration.
+	Obtain(ctx context.Context, expiration time.Duration) error
+	// Peek reports whether the lock is currently held by anyone.
+	Peek(ctx context.Context) (bool, error)
+	// Refresh extends the expiration of a lock this instance holds.
+	Refresh(ctx context.Context, expiration time.Duration) error
+	// Release releases the lock.
+	Release(ctx context.Context) error
+}
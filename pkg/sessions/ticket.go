@@ -0,0 +1,59 @@
+package sessions
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+)
+
+// Ticket identifies a session held in a server-side backing store (Redis,
+// DynamoDB, Memcached). Only the ticket ID is carried in the client's
+// cookie; the encoded SessionState itself never leaves the backing store.
+type Ticket struct {
+	ID string
+}
+
+// NewTicket creates a Ticket wrapping a new random session ID.
+func NewTicket() (*Ticket, error) {
+	rawID := make([]byte, 16)
+	if _, err := rand.Read(rawID); err != nil {
+		return nil, fmt.Errorf("failed to create session ticket ID: %w", err)
+	}
+	return &Ticket{ID: base64.RawURLEncoding.EncodeToString(rawID)}, nil
+}
+
+// SetCookie writes the ticket as the session cookie on rw.
+func (t *Ticket) SetCookie(rw http.ResponseWriter, req *http.Request, cookieOpts *options.Cookie) {
+	http.SetCookie(rw, MakeCookie(req, cookieOpts, t.ID))
+}
+
+// TicketFromRequest reads the ticket carried in the session cookie on req.
+func TicketFromRequest(req *http.Request, cookieOpts *options.Cookie) (*Ticket, error) {
+	c, err := req.Cookie(cookieOpts.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving session cookie: %w", err)
+	}
+	return &Ticket{ID: c.Value}, nil
+}
+
+// TicketForSave returns the ticket already carried on req, if any, so that a
+// Save which is persisting a refreshed SessionState reuses the same
+// server-side key (and thus the same key a Lock may be held against)
+// instead of orphaning it. A new ticket is minted only when req carries
+// none.
+func TicketForSave(req *http.Request, cookieOpts *options.Cookie) (*Ticket, error) {
+	if ticket, err := TicketFromRequest(req, cookieOpts); err == nil {
+		return ticket, nil
+	}
+	return NewTicket()
+}
+
+// ClearTicketCookie removes the session cookie from the client.
+func ClearTicketCookie(rw http.ResponseWriter, req *http.Request, cookieOpts *options.Cookie) {
+	clearCookie := MakeCookie(req, cookieOpts, "")
+	clearCookie.MaxAge = -1
+	http.SetCookie(rw, clearCookie)
+}
@@ -0,0 +1,69 @@
+package sessions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+)
+
+func testCookieOpts() *options.Cookie {
+	return &options.Cookie{
+		Name:   "_oauth2_proxy",
+		Path:   "/",
+		Expire: time.Hour,
+	}
+}
+
+func TestTicketSetCookieRoundTrip(t *testing.T) {
+	ticket, err := NewTicket()
+	if err != nil {
+		t.Fatalf("unexpected error creating ticket: %v", err)
+	}
+
+	cookieOpts := testCookieOpts()
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ticket.SetCookie(rw, req, cookieOpts)
+
+	result := rw.Result()
+	defer result.Body.Close()
+
+	req2 := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range result.Cookies() {
+		req2.AddCookie(c)
+	}
+
+	got, err := TicketFromRequest(req2, cookieOpts)
+	if err != nil {
+		t.Fatalf("unexpected error reading ticket: %v", err)
+	}
+	if got.ID != ticket.ID {
+		t.Errorf("ID = %q, want %q", got.ID, ticket.ID)
+	}
+}
+
+func TestTicketFromRequestMissingCookie(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, err := TicketFromRequest(req, testCookieOpts()); err == nil {
+		t.Fatal("expected an error when the session cookie is absent")
+	}
+}
+
+func TestClearTicketCookieExpiresImmediately(t *testing.T) {
+	cookieOpts := testCookieOpts()
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	ClearTicketCookie(rw, req, cookieOpts)
+
+	cookies := rw.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected exactly one cookie to be set, got %d", len(cookies))
+	}
+	if cookies[0].MaxAge >= 0 {
+		t.Errorf("MaxAge = %d, want a negative value to expire the cookie", cookies[0].MaxAge)
+	}
+}
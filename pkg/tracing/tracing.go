@@ -0,0 +1,106 @@
+// Package tracing configures OpenTelemetry and provides the spans
+// oauth2-proxy produces around OAuth flows and upstream requests.
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/oauth2-proxy/oauth2-proxy/pkg/apis/options"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+// Span names for the OAuth flows and upstream requests oauth2-proxy traces.
+const (
+	SpanOAuthStart    = "oauth2_proxy.oauth.start"
+	SpanOAuthCallback = "oauth2_proxy.oauth.callback"
+	SpanTokenExchange = "oauth2_proxy.oauth.token_exchange"
+	SpanUserInfo      = "oauth2_proxy.oauth.userinfo"
+	SpanUpstream      = "oauth2_proxy.upstream"
+)
+
+// Init configures the global OpenTelemetry tracer provider and text-map
+// propagator described by opts, and returns a shutdown func that flushes and
+// closes the exporter. If opts.OTLPEndpoint is empty, tracing is disabled
+// and Init returns a no-op shutdown func.
+func Init(ctx context.Context, opts options.OTelOptions) (func(context.Context) error, error) {
+	if opts.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	driverOpts := []otlpgrpc.Option{otlpgrpc.WithEndpoint(opts.OTLPEndpoint)}
+	if opts.Insecure {
+		driverOpts = append(driverOpts, otlpgrpc.WithInsecure())
+	} else {
+		creds, err := otlpTLSCredentials(opts)
+		if err != nil {
+			return nil, err
+		}
+		driverOpts = append(driverOpts, otlpgrpc.WithTLSCredentials(creds))
+	}
+
+	exporter, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(driverOpts...))
+	if err != nil {
+		return nil, fmt.Errorf("error creating otlp exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(semconv.ServiceNameKey.String(opts.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("error creating otel resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.TraceIDRatioBased(opts.SamplingRatio)}),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// otlpTLSCredentials builds the gRPC transport credentials used to connect
+// to the OTLP collector, optionally trusting a private CA.
+func otlpTLSCredentials(opts options.OTelOptions) (credentials.TransportCredentials, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: opts.InsecureSkipTLSVerify} //nolint:gosec // explicit opt-in flag
+
+	if opts.CAPath != "" {
+		ca, err := ioutil.ReadFile(opts.CAPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading otel-exporter-otlp-ca-path: %w", err)
+		}
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(ca)
+		tlsConfig.RootCAs = pool
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// Tracer returns the oauth2-proxy tracer, backed by whatever tracer
+// provider Init configured (or the no-op provider if tracing is disabled).
+func Tracer() trace.Tracer {
+	return otel.Tracer("github.com/oauth2-proxy/oauth2-proxy")
+}
+
+// StartSpan starts a span with the given name, propagating baggage already
+// present on ctx.
+func StartSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name)
+}